@@ -0,0 +1,93 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ipv6
+
+import "net"
+
+// A Message represents an IO message.
+//
+//	type Message struct {
+//		Buffers [][]byte
+//		OOB     []byte
+//		Addr    net.Addr
+//		N       int
+//		NN      int
+//		Flags   int
+//	}
+//
+// The Buffers field represents a list of contiguous buffers, which
+// can be used for vectored I/O, for example, putting a header and a
+// payload in each slice. When writing, the Buffers field must
+// contain at least one byte to write. When reading, the Buffers
+// field will always contain a byte to read.
+//
+// The OOB field contains protocol-specific control messages, the
+// output of a ControlMessage's Marshal on write, and a buffer for
+// Parse on read.
+//
+// The Addr field specifies a destination address when writing. It
+// can be nil when the underlying protocol of the endpoint uses
+// connection-oriented communication. After a successful read, it may
+// contain the source address on the received packet.
+//
+// The N field indicates the number of bytes read or written
+// from/to Buffers.
+//
+// The NN field indicates the number of bytes read or written
+// from/to OOB.
+//
+// The Flags field contains protocol-specific information on the
+// received message.
+type Message struct {
+	Buffers [][]byte
+	OOB     []byte
+	Addr    net.Addr
+	N       int
+	NN      int
+	Flags   int
+}
+
+// ReadBatch reads a batch of messages.
+//
+// The provided flags is a set of platform-dependent flags, such as
+// syscall.MSG_PEEK.
+//
+// On a successful read it returns the number of messages received, up
+// to len(ms). On each received message, the corresponding Buffers,
+// OOB, Addr, N and NN fields are filled in.
+//
+// On 64-bit Linux (amd64, arm64, riscv64, loong64), ReadBatch uses
+// recvmmsg(2) to receive multiple datagrams in a single system call,
+// amortizing the per-call overhead across the batch. On other
+// platforms, including the BSDs, Windows and other Linux
+// architectures, it falls back to reading one message at a time with
+// ReadFrom; on the fallback path each Message's Buffers must contain
+// exactly one buffer, and flags is ignored.
+func (c *PacketConn) ReadBatch(ms []Message, flags int) (int, error) {
+	if !c.ok() {
+		return 0, errInvalidConn
+	}
+	return c.readBatch(ms, flags)
+}
+
+// WriteBatch writes a batch of messages.
+//
+// The provided flags is a set of platform-dependent flags, such as
+// syscall.MSG_DONTWAIT.
+//
+// It returns the number of messages written on a successful write.
+//
+// On 64-bit Linux (amd64, arm64, riscv64, loong64), WriteBatch uses
+// sendmmsg(2) to send multiple datagrams in a single system call. On
+// other platforms, including the BSDs, Windows and other Linux
+// architectures, it falls back to writing one message at a time with
+// WriteTo; on the fallback path each Message's Buffers must contain
+// exactly one buffer, and flags is ignored.
+func (c *PacketConn) WriteBatch(ms []Message, flags int) (int, error) {
+	if !c.ok() {
+		return 0, errInvalidConn
+	}
+	return c.writeBatch(ms, flags)
+}
@@ -0,0 +1,80 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ipv6
+
+import "errors"
+
+// errMultipleBuffers is returned by the fallback batch I/O path,
+// which has no vectored I/O system call underneath it and so can
+// only read or write a single buffer per message.
+var errMultipleBuffers = errors.New("ipv6: multiple Buffers per Message not supported on this platform")
+
+// readBatchFallback reads one message at a time using ReadFrom,
+// filling in up to len(ms) entries of ms. It is used on platforms
+// that have no batched receive system call, and as a fallback when
+// the underlying connection is not backed by a file descriptor this
+// package knows how to batch.
+func (c *PacketConn) readBatchFallback(ms []Message, flags int) (int, error) {
+	n := 0
+	for i := range ms {
+		if len(ms[i].Buffers) == 0 {
+			break
+		}
+		if len(ms[i].Buffers) > 1 {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, errMultipleBuffers
+		}
+		nn, cm, src, err := c.ReadFrom(ms[i].Buffers[0])
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		ms[i].N = nn
+		ms[i].Addr = src
+		ms[i].NN = 0
+		if cm != nil && len(ms[i].OOB) > 0 {
+			ms[i].NN = copy(ms[i].OOB, cm.Marshal())
+		}
+		n++
+	}
+	return n, nil
+}
+
+// writeBatchFallback writes one message at a time using WriteTo.
+func (c *PacketConn) writeBatchFallback(ms []Message, flags int) (int, error) {
+	n := 0
+	for i := range ms {
+		if len(ms[i].Buffers) == 0 {
+			break
+		}
+		if len(ms[i].Buffers) > 1 {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, errMultipleBuffers
+		}
+		var cm *ControlMessage
+		if len(ms[i].OOB) > 0 {
+			cm = &ControlMessage{}
+			if err := cm.Parse(ms[i].OOB); err != nil {
+				return n, err
+			}
+		}
+		nn, err := c.WriteTo(ms[i].Buffers[0], cm, ms[i].Addr)
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		ms[i].N = nn
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,225 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && (amd64 || arm64 || riscv64 || loong64)
+
+package ipv6
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// An mmsghdr mirrors the kernel's struct mmsghdr, as consumed by the
+// recvmmsg(2)/sendmmsg(2) system calls. golang.org/x/sys/unix does
+// not wrap either call, so the array passed to the raw system call is
+// built by hand here, following the same approach as other direct
+// recvmmsg/sendmmsg callers.
+type mmsghdr struct {
+	Hdr unix.Msghdr
+	Len uint32
+	_   [4]byte
+}
+
+// readBatch receives up to len(ms) datagrams in a single recvmmsg(2)
+// call, amortizing the per-call overhead of the underlying system
+// call across the batch.
+func (c *PacketConn) readBatch(ms []Message, flags int) (int, error) {
+	udp, ok := c.c.(*net.UDPConn)
+	if !ok {
+		return c.readBatchFallback(ms, flags)
+	}
+	sc, err := udp.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	hs, iovs, rsas := newMmsghdrsForRead(ms)
+	var n int
+	var rerr error
+	cerr := sc.Read(func(fd uintptr) bool {
+		n, rerr = recvmmsg(fd, hs, flags)
+		return rerr != syscall.EAGAIN
+	})
+	_ = iovs // keep the backing Iovec slices alive until the syscall returns
+	// Check the poller error first: rerr may be a stale EAGAIN left
+	// over from an earlier retry, while cerr holds the real reason
+	// the poller stopped (deadline exceeded, connection closed).
+	if cerr != nil {
+		return 0, cerr
+	}
+	if rerr != nil {
+		return 0, os.NewSyscallError("recvmmsg", rerr)
+	}
+	for i := 0; i < n; i++ {
+		ms[i].N = int(hs[i].Len)
+		ms[i].NN = int(hs[i].Hdr.Controllen)
+		ms[i].Flags = int(hs[i].Hdr.Flags)
+		if hs[i].Hdr.Namelen > 0 {
+			ms[i].Addr = udpAddrFromSockaddrInet6(&rsas[i])
+		}
+	}
+	return n, nil
+}
+
+// writeBatch sends up to len(ms) datagrams in a single sendmmsg(2)
+// call.
+func (c *PacketConn) writeBatch(ms []Message, flags int) (int, error) {
+	udp, ok := c.c.(*net.UDPConn)
+	if !ok {
+		return c.writeBatchFallback(ms, flags)
+	}
+	sc, err := udp.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	hs, iovs := newMmsghdrsForWrite(ms)
+	var n int
+	var rerr error
+	cerr := sc.Write(func(fd uintptr) bool {
+		n, rerr = sendmmsg(fd, hs, flags)
+		return rerr != syscall.EAGAIN
+	})
+	_ = iovs
+	// See readBatch: check the poller error first so a stale EAGAIN
+	// doesn't mask the real reason the poller stopped.
+	if cerr != nil {
+		return n, cerr
+	}
+	if rerr != nil {
+		return n, os.NewSyscallError("sendmmsg", rerr)
+	}
+	for i := 0; i < n; i++ {
+		ms[i].N = int(hs[i].Len)
+	}
+	return n, nil
+}
+
+// newMmsghdrsForWrite builds the mmsghdr array and the Iovec slices
+// it points into for a sendmmsg(2) call. The Iovec slices are
+// returned alongside so the caller can keep them reachable for the
+// garbage collector until the system call that reads them has
+// returned.
+func newMmsghdrsForWrite(ms []Message) ([]mmsghdr, [][]unix.Iovec) {
+	hs := make([]mmsghdr, len(ms))
+	iovs := make([][]unix.Iovec, len(ms))
+	for i := range ms {
+		iovs[i] = make([]unix.Iovec, len(ms[i].Buffers))
+		for j, b := range ms[i].Buffers {
+			if len(b) > 0 {
+				iovs[i][j].Base = &b[0]
+				iovs[i][j].SetLen(len(b))
+			}
+		}
+		if len(iovs[i]) > 0 {
+			hs[i].Hdr.Iov = &iovs[i][0]
+			hs[i].Hdr.Iovlen = uint64(len(iovs[i]))
+		}
+		if len(ms[i].OOB) > 0 {
+			hs[i].Hdr.Control = &ms[i].OOB[0]
+			hs[i].Hdr.Controllen = uint64(len(ms[i].OOB))
+		}
+		if a, ok := ms[i].Addr.(*net.UDPAddr); ok && a != nil {
+			rsa, salen := sockaddrInet6(a)
+			hs[i].Hdr.Name = (*byte)(unsafe.Pointer(rsa))
+			hs[i].Hdr.Namelen = salen
+		}
+	}
+	return hs, iovs
+}
+
+// newMmsghdrsForRead builds the mmsghdr array and the Iovec slices
+// for a recvmmsg(2) call. Unlike the write side, each message also
+// gets a scratch sockaddr_in6 for the kernel to fill in with the
+// sender's address; the caller decodes it into Message.Addr with
+// udpAddrFromSockaddrInet6 once the syscall returns.
+func newMmsghdrsForRead(ms []Message) ([]mmsghdr, [][]unix.Iovec, []unix.RawSockaddrInet6) {
+	hs := make([]mmsghdr, len(ms))
+	iovs := make([][]unix.Iovec, len(ms))
+	rsas := make([]unix.RawSockaddrInet6, len(ms))
+	for i := range ms {
+		iovs[i] = make([]unix.Iovec, len(ms[i].Buffers))
+		for j, b := range ms[i].Buffers {
+			if len(b) > 0 {
+				iovs[i][j].Base = &b[0]
+				iovs[i][j].SetLen(len(b))
+			}
+		}
+		if len(iovs[i]) > 0 {
+			hs[i].Hdr.Iov = &iovs[i][0]
+			hs[i].Hdr.Iovlen = uint64(len(iovs[i]))
+		}
+		if len(ms[i].OOB) > 0 {
+			hs[i].Hdr.Control = &ms[i].OOB[0]
+			hs[i].Hdr.Controllen = uint64(len(ms[i].OOB))
+		}
+		hs[i].Hdr.Name = (*byte)(unsafe.Pointer(&rsas[i]))
+		hs[i].Hdr.Namelen = uint32(unsafe.Sizeof(rsas[i]))
+	}
+	return hs, iovs, rsas
+}
+
+// sockaddrInet6 builds a struct sockaddr_in6 for addr. Unlike the
+// native-byte-order ints carried inside cmsg payloads, sin6_port is
+// always network byte order (big-endian), per POSIX.
+func sockaddrInet6(addr *net.UDPAddr) (*unix.RawSockaddrInet6, uint32) {
+	var rsa unix.RawSockaddrInet6
+	rsa.Family = unix.AF_INET6
+	binary.BigEndian.PutUint16((*[2]byte)(unsafe.Pointer(&rsa.Port))[:], uint16(addr.Port))
+	if ip16 := addr.IP.To16(); ip16 != nil {
+		copy(rsa.Addr[:], ip16)
+	}
+	if addr.Zone != "" {
+		if ifi, err := net.InterfaceByName(addr.Zone); err == nil {
+			rsa.Scope_id = uint32(ifi.Index)
+		}
+	}
+	return &rsa, uint32(unsafe.Sizeof(rsa))
+}
+
+// udpAddrFromSockaddrInet6 decodes a struct sockaddr_in6 filled in by
+// the kernel as the sender address of a received datagram. Like
+// sockaddrInet6, sin6_port is network byte order, not the native
+// order used by cmsg payload ints.
+func udpAddrFromSockaddrInet6(rsa *unix.RawSockaddrInet6) *net.UDPAddr {
+	a := &net.UDPAddr{
+		IP:   append(net.IP(nil), rsa.Addr[:]...),
+		Port: int(binary.BigEndian.Uint16((*[2]byte)(unsafe.Pointer(&rsa.Port))[:])),
+	}
+	if rsa.Scope_id != 0 {
+		if ifi, err := net.InterfaceByIndex(int(rsa.Scope_id)); err == nil {
+			a.Zone = ifi.Name
+		}
+	}
+	return a
+}
+
+// recvmmsg issues the recvmmsg(2) system call directly: neither the
+// standard library nor golang.org/x/sys/unix exposes a wrapper for it.
+func recvmmsg(fd uintptr, hs []mmsghdr, flags int) (int, error) {
+	if len(hs) == 0 {
+		return 0, nil
+	}
+	n, _, errno := syscall.RawSyscall6(unix.SYS_RECVMMSG, fd, uintptr(unsafe.Pointer(&hs[0])), uintptr(len(hs)), uintptr(flags), 0, 0)
+	if errno != 0 {
+		return int(n), errno
+	}
+	return int(n), nil
+}
+
+// sendmmsg issues the sendmmsg(2) system call directly.
+func sendmmsg(fd uintptr, hs []mmsghdr, flags int) (int, error) {
+	if len(hs) == 0 {
+		return 0, nil
+	}
+	n, _, errno := syscall.RawSyscall6(unix.SYS_SENDMMSG, fd, uintptr(unsafe.Pointer(&hs[0])), uintptr(len(hs)), uintptr(flags), 0, 0)
+	if errno != 0 {
+		return int(n), errno
+	}
+	return int(n), nil
+}
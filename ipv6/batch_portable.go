@@ -0,0 +1,18 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !(linux && (amd64 || arm64 || riscv64 || loong64))
+
+package ipv6
+
+// readBatch and writeBatch fall back to issuing one system call per
+// message on platforms without a batched send/receive primitive, or
+// on Linux architectures batch_mmsg.go doesn't cover yet.
+func (c *PacketConn) readBatch(ms []Message, flags int) (int, error) {
+	return c.readBatchFallback(ms, flags)
+}
+
+func (c *PacketConn) writeBatch(ms []Message, flags int) (int, error) {
+	return c.writeBatchFallback(ms, flags)
+}
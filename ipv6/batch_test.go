@@ -0,0 +1,100 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ipv6_test
+
+import (
+	"bytes"
+	"net"
+	"runtime"
+	"testing"
+
+	"github.com/token-test-tomas/net/internal/iana"
+	"github.com/token-test-tomas/net/ipv6"
+	"github.com/token-test-tomas/net/nettest"
+)
+
+func TestPacketConnReadWriteBatchUnicastUDP(t *testing.T) {
+	switch runtime.GOOS {
+	case "fuchsia", "hurd", "js", "nacl", "plan9", "wasip1", "windows":
+		t.Skipf("not supported on %s", runtime.GOOS)
+	}
+	if _, err := nettest.RoutedInterface("ip6", net.FlagUp|net.FlagLoopback); err != nil {
+		t.Skip("ipv6 is not enabled for loopback interface")
+	}
+
+	c, err := nettest.NewLocalPacketListener("udp6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	p := ipv6.NewPacketConn(c)
+	defer p.Close()
+
+	dst := c.LocalAddr()
+	cf := ipv6.FlagTrafficClass | ipv6.FlagHopLimit
+	if err := p.SetControlMessage(cf, true); err != nil {
+		if protocolNotSupported(err) {
+			t.Skipf("not supported on %s", runtime.GOOS)
+		}
+		t.Fatal(err)
+	}
+
+	ifi, _ := nettest.RoutedInterface("ip6", net.FlagUp|net.FlagLoopback)
+
+	const batchSize = 3
+	trafficClasses := []int{iana.DiffServAF11, iana.DiffServAF12, iana.DiffServAF13}
+	wb := []byte("HELLO-R-U-THERE")
+	wms := make([]ipv6.Message, batchSize)
+	wantHopLimit := make([]int, batchSize)
+	wantTrafficClass := make([]int, batchSize)
+	for i := range wms {
+		cm := ipv6.ControlMessage{
+			TrafficClass: trafficClasses[i],
+			HopLimit:     i + 1,
+			Src:          net.IPv6loopback,
+		}
+		if ifi != nil {
+			cm.IfIndex = ifi.Index
+		}
+		wantHopLimit[i] = cm.HopLimit
+		wantTrafficClass[i] = cm.TrafficClass
+		wms[i].Buffers = [][]byte{wb}
+		wms[i].Addr = dst
+		wms[i].OOB = cm.Marshal()
+	}
+	if n, err := p.WriteBatch(wms, 0); err != nil {
+		t.Fatal(err)
+	} else if n != batchSize {
+		t.Fatalf("got %d; want %d", n, batchSize)
+	}
+
+	rms := make([]ipv6.Message, batchSize)
+	for i := range rms {
+		rms[i].Buffers = [][]byte{make([]byte, 128)}
+		rms[i].OOB = ipv6.NewControlMessage(cf)
+	}
+	n, err := p.ReadBatch(rms, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != batchSize {
+		t.Fatalf("got %d; want %d", n, batchSize)
+	}
+	for i, m := range rms[:n] {
+		if !bytes.Equal(m.Buffers[0][:m.N], wb) {
+			t.Fatalf("got %v; want %v", m.Buffers[0][:m.N], wb)
+		}
+		var cm ipv6.ControlMessage
+		if err := cm.Parse(m.OOB[:m.NN]); err != nil {
+			t.Fatal(err)
+		}
+		if cm.HopLimit != wantHopLimit[i] {
+			t.Fatalf("got HopLimit=%d; want %d", cm.HopLimit, wantHopLimit[i])
+		}
+		if cm.TrafficClass != wantTrafficClass[i] {
+			t.Fatalf("got TrafficClass=%d; want %d", cm.TrafficClass, wantTrafficClass[i])
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ipv6
+
+import "net"
+
+// A ControlFlags represents per packet basis IP-level socket option
+// flags that the caller wants to receive with PacketConn's ReadFrom
+// or RawConn's ReadFrom, or wants to send with PacketConn's WriteTo
+// or RawConn's WriteTo.
+type ControlFlags uint
+
+const (
+	FlagTrafficClass ControlFlags = 1 << iota // pass the traffic class on the received packet
+	FlagHopLimit                              // pass the hop limit on the received packet
+	FlagSrc                                   // pass the source address on the received packet
+	FlagDst                                   // pass the destination address on the received packet
+	FlagInterface                             // pass the interface index on the received packet
+	FlagPathMTU                               // pass the path MTU on the received packet path
+	FlagGRO                                   // pass the received-segment size of a GRO-coalesced datagram
+)
+
+// A ControlMessage represents per packet basis IP-level socket
+// options.
+type ControlMessage struct {
+	// Receiving socket options: SetControlMessage allows to
+	// receive the options from the protocol stack using ReadFrom
+	// method of PacketConn or RawConn.
+	//
+	// Specifying socket options: ControlMessage for WriteTo
+	// method of PacketConn or RawConn allows to send the options
+	// to the protocol stack.
+	TrafficClass int    // traffic class, must be 1 <= value <= 255 when specifying
+	HopLimit     int    // hop limit, must be 1 <= value <= 255 when specifying
+	Src          net.IP // source address, specifying only
+	Dst          net.IP // destination address, receiving only
+	IfIndex      int    // interface index, must be 1 <= value when specifying
+	NextHop      net.IP // next hop address, specifying only
+	MTU          int    // path MTU, receiving only
+
+	// GSOSize requests that the kernel split a large WriteTo
+	// payload into multiple UDP/IPv6 datagrams of at most GSOSize
+	// bytes each (UDP_SEGMENT), specifying only. It is silently
+	// ignored on platforms or kernels that don't support segmentation
+	// offload.
+	GSOSize int
+
+	// GROSize reports the segment size used by the kernel to
+	// coalesce a run of incoming UDP/IPv6 datagrams into the single
+	// payload delivered by ReadFrom (UDP_GRO), receiving only. It is
+	// zero unless SetControlMessage was called with FlagGRO and the
+	// platform supports receive offload.
+	GROSize int
+}
+
+// Marshal returns the binary encoding of cm as a sequence of
+// ancillary data suitable for use as the OOB payload of a
+// WriteMsgUDP/sendmsg call.
+func (cm *ControlMessage) Marshal() []byte {
+	if cm == nil {
+		return nil
+	}
+	var b []byte
+	if cm.TrafficClass > 0 {
+		b = appendCmsg(b, ctlTrafficClass, marshalTrafficClass(cm.TrafficClass))
+	}
+	if cm.HopLimit > 0 {
+		b = appendCmsg(b, ctlHopLimit, marshalHopLimit(cm.HopLimit))
+	}
+	if cm.Src != nil || cm.IfIndex > 0 {
+		b = appendCmsg(b, ctlPacketInfo, marshalPacketInfo(cm.Src, cm.IfIndex))
+	}
+	if cm.NextHop != nil {
+		b = appendCmsg(b, ctlNextHop, marshalNextHop(cm.NextHop))
+	}
+	if cm.GSOSize > 0 {
+		b = appendCmsg(b, ctlGSOSegment, marshalGSOSize(cm.GSOSize))
+	}
+	return b
+}
+
+// Parse parses b as a sequence of ancillary data received alongside
+// a ReadMsgUDP/recvmsg call and stores the result in cm.
+func (cm *ControlMessage) Parse(b []byte) error {
+	for len(b) > 0 {
+		typ, data, rest, err := nextCmsg(b)
+		if err != nil {
+			return err
+		}
+		switch typ {
+		case ctlTrafficClass:
+			cm.TrafficClass = parseTrafficClass(data)
+		case ctlHopLimit:
+			cm.HopLimit = parseHopLimit(data)
+		case ctlPacketInfo:
+			cm.Dst, cm.IfIndex = parsePacketInfo(data)
+		case ctlPathMTU:
+			cm.Dst, cm.MTU = parsePathMTU(data)
+		case ctlGSOSegment:
+			cm.GROSize = parseGROSize(data)
+		}
+		b = rest
+	}
+	return nil
+}
+
+// NewControlMessage returns a new buffer large enough to hold the
+// ancillary data requested by cf, for use as the OOB buffer of a
+// ReadBatch call.
+func NewControlMessage(cf ControlFlags) []byte {
+	var n int
+	if cf&FlagTrafficClass != 0 {
+		n += cmsgSpace(4)
+	}
+	if cf&FlagHopLimit != 0 {
+		n += cmsgSpace(4)
+	}
+	if cf&(FlagSrc|FlagDst|FlagInterface) != 0 {
+		n += cmsgSpace(pktInfoSize)
+	}
+	if cf&FlagPathMTU != 0 {
+		n += cmsgSpace(mtuInfoSize)
+	}
+	if cf&FlagGRO != 0 {
+		n += cmsgSpace(2)
+	}
+	return make([]byte, n)
+}
@@ -0,0 +1,129 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ipv6
+
+import (
+	"errors"
+	"net"
+	"sync"
+)
+
+var errInvalidConn = errors.New("invalid connection")
+
+// A genericOpt represents the per packet basis option state that is
+// shared by PacketConn and RawConn.
+type genericOpt struct {
+	mu sync.RWMutex
+	cf ControlFlags
+}
+
+func (c *genericOpt) ok() bool { return c != nil }
+
+// A PacketConn represents a packet network endpoint that uses the
+// IPv6 transport. It is used to control basic IP-level socket
+// options such as traffic class and hop limit.
+type PacketConn struct {
+	genericOpt
+	c net.PacketConn
+}
+
+// NewPacketConn returns a new PacketConn using c as its underlying
+// transport.
+func NewPacketConn(c net.PacketConn) *PacketConn {
+	return &PacketConn{c: c}
+}
+
+// SetControlMessage allows to receive the per packet basis IP-level
+// socket options.
+func (c *PacketConn) SetControlMessage(cf ControlFlags, on bool) error {
+	if !c.ok() {
+		return errInvalidConn
+	}
+	if cf&FlagGRO != 0 {
+		if err := setUDPGRO(c.c, on); err != nil {
+			return err
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if on {
+		c.cf |= cf
+	} else {
+		c.cf &^= cf
+	}
+	return nil
+}
+
+// WriteTo writes a payload of the IPv6 datagram, to the destination
+// address dst through the endpoint c, copying the payload from b. It
+// returns the number of bytes written. The control message cm allows
+// the IPv6 header fields and the datagram path to be specified. The
+// cm may be nil if control of the outgoing datagram is not required.
+func (c *PacketConn) WriteTo(b []byte, cm *ControlMessage, dst net.Addr) (int, error) {
+	if !c.ok() {
+		return 0, errInvalidConn
+	}
+	if udp, ok := c.c.(*net.UDPConn); ok && cm != nil {
+		if oob := cm.Marshal(); len(oob) > 0 {
+			a, ok := dst.(*net.UDPAddr)
+			if !ok {
+				return 0, &net.OpError{Op: "write", Net: "udp6", Source: udp.LocalAddr(), Addr: dst, Err: net.InvalidAddrError("non-UDP address")}
+			}
+			n, _, err := udp.WriteMsgUDP(b, oob, a)
+			return n, err
+		}
+	}
+	return c.c.WriteTo(b, dst)
+}
+
+// ReadFrom reads a payload of the received IPv6 datagram, from the
+// endpoint c, copying the payload into b. It returns the number of
+// bytes copied and the source address of the datagram.
+func (c *PacketConn) ReadFrom(b []byte) (n int, cm *ControlMessage, src net.Addr, err error) {
+	if !c.ok() {
+		return 0, nil, nil, errInvalidConn
+	}
+	c.mu.RLock()
+	cf := c.cf
+	c.mu.RUnlock()
+	if udp, ok := c.c.(*net.UDPConn); ok && cf != 0 {
+		oob := NewControlMessage(cf)
+		var nn, flags int
+		n, nn, flags, src, err = udp.ReadMsgUDP(b, oob)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		cm = &ControlMessage{}
+		if err := cm.Parse(oob[:nn]); err != nil {
+			return 0, nil, nil, err
+		}
+		_ = flags
+		return n, cm, src, nil
+	}
+	n, src, err = c.c.ReadFrom(b)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if cf != 0 {
+		cm = &ControlMessage{}
+	}
+	return n, cm, src, nil
+}
+
+// Close closes the endpoint c.
+func (c *PacketConn) Close() error {
+	if !c.ok() {
+		return errInvalidConn
+	}
+	return c.c.Close()
+}
+
+// LocalAddr returns the local network address of the endpoint c.
+func (c *PacketConn) LocalAddr() net.Addr {
+	if !c.ok() {
+		return nil
+	}
+	return c.c.LocalAddr()
+}
@@ -0,0 +1,40 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ipv6
+
+import (
+	"errors"
+	"net"
+)
+
+// Well-known values for ExtendedError's Origin field, mirroring the
+// kernel's SO_EE_ORIGIN_* constants.
+const (
+	ExtendedErrorOriginNone     = 0
+	ExtendedErrorOriginLocal    = 1
+	ExtendedErrorOriginICMP6    = 3
+	ExtendedErrorOriginTXStatus = 4
+)
+
+// An ExtendedError represents an asynchronous ICMPv6 error, such as
+// Destination Unreachable, Packet Too Big, Time Exceeded or Parameter
+// Problem, that the kernel queued on a PacketConn's error queue in
+// response to a previously sent unicast datagram. It is read back
+// with ReadExtendedErr after SetRecvError(true).
+type ExtendedError struct {
+	Origin int // one of the ExtendedErrorOrigin* constants
+	Type   int // ICMPv6 type, valid when Origin is ExtendedErrorOriginICMP6
+	Code   int // ICMPv6 code, valid when Origin is ExtendedErrorOriginICMP6
+
+	// Info carries extra data attached to some ICMPv6 errors, most
+	// notably the next-hop MTU reported by a Packet Too Big error,
+	// useful for path MTU discovery.
+	Info int
+
+	Offender    net.IP // address of the node that generated the error
+	OriginalDst net.IP // destination address of the datagram that provoked the error
+}
+
+var errExtendedErrNotSupported = errors.New("ipv6: extended error notifications not supported")
@@ -0,0 +1,150 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+
+package ipv6
+
+import (
+	"net"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// sizeofSockExtendedErr is sizeof(struct sock_extended_err) on Linux:
+// ee_errno uint32; ee_origin, ee_type, ee_code, ee_pad uint8;
+// ee_info uint32; ee_data uint32.
+const sizeofSockExtendedErr = 16
+
+// SetRecvError enables or disables delivery of ICMPv6 error
+// notifications to c's error queue (IPV6_RECVERR), readable with
+// ReadExtendedErr. Without it, ICMPv6 errors provoked by datagrams
+// sent on c are only visible as asynchronous, connection-less write
+// failures that never surface on c itself.
+func (c *PacketConn) SetRecvError(on bool) error {
+	if !c.ok() {
+		return errInvalidConn
+	}
+	sc, ok := c.c.(interface {
+		SyscallConn() (syscall.RawConn, error)
+	})
+	if !ok {
+		return errExtendedErrNotSupported
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var serr error
+	cerr := rc.Control(func(fd uintptr) {
+		serr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_RECVERR, boolToInt(on))
+	})
+	if serr != nil {
+		return serr
+	}
+	return cerr
+}
+
+// ReadExtendedErr reads a single queued ICMPv6 error notification
+// from c's error queue, copying as much of the offending datagram as
+// fits into b. It returns the number of bytes of the offending
+// datagram copied into b and a description of the error.
+//
+// ReadExtendedErr requires a prior, successful call to
+// SetRecvError(true).
+func (c *PacketConn) ReadExtendedErr(b []byte) (n int, ee *ExtendedError, err error) {
+	if !c.ok() {
+		return 0, nil, errInvalidConn
+	}
+	udp, ok := c.c.(*net.UDPConn)
+	if !ok {
+		return 0, nil, errExtendedErrNotSupported
+	}
+	sc, err := udp.SyscallConn()
+	if err != nil {
+		return 0, nil, err
+	}
+	oob := make([]byte, 512)
+	var rn, roob int
+	var rerr error
+	cerr := sc.Read(func(fd uintptr) bool {
+		rn, roob, _, _, rerr = unix.Recvmsg(int(fd), b, oob, unix.MSG_ERRQUEUE)
+		return rerr != unix.EAGAIN
+	})
+	if cerr != nil {
+		return 0, nil, cerr
+	}
+	if rerr != nil {
+		return 0, nil, os.NewSyscallError("recvmsg", rerr)
+	}
+	ee, err = parseExtendedErr(oob[:roob])
+	if err != nil {
+		return 0, nil, err
+	}
+	return rn, ee, nil
+}
+
+// parseExtendedErr scans the ancillary data returned alongside a
+// MSG_ERRQUEUE recvmsg for an IPV6_RECVERR message and, opportunistically,
+// an IPV6_PKTINFO message carrying the original destination address.
+func parseExtendedErr(b []byte) (*ExtendedError, error) {
+	var ee *ExtendedError
+	var dst net.IP
+	for len(b) >= syscall.CmsgLen(0) {
+		h := (*syscall.Cmsghdr)(unsafe.Pointer(&b[0]))
+		l := int(h.Len)
+		if l < syscall.CmsgLen(0) || l > len(b) {
+			return nil, errInvalidControlMessage
+		}
+		data := b[syscall.CmsgLen(0):l]
+		switch {
+		case h.Level == syscall.IPPROTO_IPV6 && h.Type == unix.IPV6_RECVERR:
+			e, err := decodeSockExtendedErr(data)
+			if err != nil {
+				return nil, err
+			}
+			ee = e
+		case h.Level == syscall.IPPROTO_IPV6 && h.Type == unix.IPV6_PKTINFO:
+			dst, _ = parsePacketInfo(data)
+		}
+		b = b[syscall.CmsgSpace(l-syscall.CmsgLen(0)):]
+	}
+	if ee == nil {
+		return nil, errExtendedErrNotSupported
+	}
+	ee.OriginalDst = dst
+	return ee, nil
+}
+
+// decodeSockExtendedErr decodes a struct sock_extended_err, optionally
+// followed by the struct sockaddr_in6 identifying the offending node
+// (SO_EE_OFFENDER).
+func decodeSockExtendedErr(b []byte) (*ExtendedError, error) {
+	if len(b) < sizeofSockExtendedErr {
+		return nil, errInvalidControlMessage
+	}
+	ee := &ExtendedError{
+		Origin: int(b[4]),
+		Type:   int(b[5]),
+		Code:   int(b[6]),
+		Info:   int(nativeEndian.Uint32(b[8:12])),
+	}
+	// SO_EE_OFFENDER: struct sockaddr_in6 immediately follows the
+	// fixed-size sock_extended_err, family(2) port(2) flowinfo(4)
+	// addr(16) scope_id(4). sin6_family is a native-order 16-bit C
+	// integer, not a wire-format field, so it must be read with
+	// nativeEndian too.
+	if rest := b[sizeofSockExtendedErr:]; len(rest) >= sockaddrIn6Len {
+		family := nativeEndian.Uint16(rest[:2])
+		if family == unix.AF_INET6 {
+			addr := make(net.IP, 16)
+			copy(addr, rest[8:24])
+			ee.Offender = addr
+		}
+	}
+	return ee, nil
+}
@@ -0,0 +1,32 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package ipv6
+
+// IPV6_RECVERR and MSG_ERRQUEUE are Linux-specific, so SetRecvError
+// and ReadExtendedErr report errExtendedErrNotSupported everywhere
+// else.
+
+// SetRecvError enables or disables delivery of ICMPv6 error
+// notifications to c's error queue. Enabling always fails on this
+// platform; disabling is a no-op that returns nil.
+func (c *PacketConn) SetRecvError(on bool) error {
+	if !c.ok() {
+		return errInvalidConn
+	}
+	if !on {
+		return nil
+	}
+	return errExtendedErrNotSupported
+}
+
+// ReadExtendedErr always fails on this platform.
+func (c *PacketConn) ReadExtendedErr(b []byte) (n int, ee *ExtendedError, err error) {
+	if !c.ok() {
+		return 0, nil, errInvalidConn
+	}
+	return 0, nil, errExtendedErrNotSupported
+}
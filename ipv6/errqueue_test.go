@@ -0,0 +1,80 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ipv6_test
+
+import (
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/token-test-tomas/net/ipv6"
+	"github.com/token-test-tomas/net/nettest"
+)
+
+// TestPacketConnReadExtendedErrUnicastUDP exercises the IPV6_RECVERR
+// based error queue: it provokes an ICMPv6 Destination Unreachable by
+// writing to a UDP6 port with nothing listening on it, and checks
+// that the error surfaces on the very socket that sent the datagram,
+// without needing a raw ICMP listener like
+// TestPacketConnReadWriteUnicastICMP does.
+func TestPacketConnReadExtendedErrUnicastUDP(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skipf("IPV6_RECVERR only supported on linux, not %s", runtime.GOOS)
+	}
+	if _, err := nettest.RoutedInterface("ip6", net.FlagUp|net.FlagLoopback); err != nil {
+		t.Skip("ipv6 is not enabled for loopback interface")
+	}
+
+	// Find a UDP6 port nobody is listening on.
+	probe, err := net.ListenPacket("udp6", "[::1]:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := probe.LocalAddr()
+	probe.Close()
+
+	c, err := nettest.NewLocalPacketListener("udp6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	p := ipv6.NewPacketConn(c)
+	defer p.Close()
+
+	if err := p.SetRecvError(true); err != nil {
+		if protocolNotSupported(err) {
+			t.Skipf("IPV6_RECVERR not supported on %s", runtime.GOOS)
+		}
+		t.Fatal(err)
+	}
+
+	if _, err := p.WriteTo([]byte("HELLO-R-U-THERE"), nil, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bound how long a missing notification can block ReadExtendedErr's
+	// underlying recvmsg, since not every sandbox delivers ICMPv6
+	// errors back to loopback UDP traffic.
+	if err := c.SetDeadline(time.Now().Add(2 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	rb := make([]byte, 128)
+	for i := 0; i < 10; i++ {
+		_, ee, err := p.ReadExtendedErr(rb)
+		if err != nil {
+			if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+				break
+			}
+			continue
+		}
+		if ee.Origin != ipv6.ExtendedErrorOriginICMP6 {
+			t.Fatalf("got origin %d; want %d", ee.Origin, ipv6.ExtendedErrorOriginICMP6)
+		}
+		return
+	}
+	t.Skip("no ICMPv6 error notification arrived; kernel/network may not have delivered one")
+}
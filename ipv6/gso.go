@@ -0,0 +1,13 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ipv6
+
+import "errors"
+
+// errGSONotSupported is returned by SetControlMessage when asked to
+// enable FlagGRO on a platform or connection type that does not
+// support UDP generic receive offload. On Linux, a kernel that
+// rejects UDP_GRO is reported by the setsockopt error instead.
+var errGSONotSupported = errors.New("ipv6: UDP GRO not supported")
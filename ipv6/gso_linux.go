@@ -0,0 +1,55 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+
+package ipv6
+
+import (
+	"net"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// UDP_SEGMENT and UDP_GRO, the SOL_UDP level sockopts backing
+// ControlMessage's GSOSize and GROSize, are Linux-only.
+const (
+	gsoSupported   = true
+	sysSOL_UDP     = syscall.IPPROTO_UDP
+	sysUDP_SEGMENT = unix.UDP_SEGMENT
+	sysUDP_GRO     = unix.UDP_GRO
+)
+
+// setUDPGRO enables or disables UDP generic receive offload on c so
+// that the kernel coalesces a run of incoming datagrams into a single
+// payload delivered by ReadFrom, with the original per-datagram size
+// reported via ControlMessage.GROSize.
+func setUDPGRO(c net.PacketConn, on bool) error {
+	sc, ok := c.(interface {
+		SyscallConn() (syscall.RawConn, error)
+	})
+	if !ok {
+		return errGSONotSupported
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var serr error
+	cerr := rc.Control(func(fd uintptr) {
+		serr = unix.SetsockoptInt(int(fd), unix.IPPROTO_UDP, unix.UDP_GRO, boolToInt(on))
+	})
+	if serr != nil {
+		return serr
+	}
+	return cerr
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,26 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix && !linux
+
+package ipv6
+
+import "net"
+
+// UDP_SEGMENT and UDP_GRO are not available outside Linux, so
+// GSOSize and GROSize are always silently dropped on these
+// platforms.
+const (
+	gsoSupported   = false
+	sysSOL_UDP     = 0
+	sysUDP_SEGMENT = 0
+	sysUDP_GRO     = 0
+)
+
+func setUDPGRO(c net.PacketConn, on bool) error {
+	if !on {
+		return nil
+	}
+	return errGSONotSupported
+}
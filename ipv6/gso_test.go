@@ -0,0 +1,67 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ipv6_test
+
+import (
+	"net"
+	"runtime"
+	"testing"
+
+	"github.com/token-test-tomas/net/ipv6"
+	"github.com/token-test-tomas/net/nettest"
+)
+
+func TestPacketConnReadWriteGSOGROUnicastUDP(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skipf("GSO/GRO only supported on linux, not %s", runtime.GOOS)
+	}
+	if _, err := nettest.RoutedInterface("ip6", net.FlagUp|net.FlagLoopback); err != nil {
+		t.Skip("ipv6 is not enabled for loopback interface")
+	}
+
+	c, err := nettest.NewLocalPacketListener("udp6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	p := ipv6.NewPacketConn(c)
+	defer p.Close()
+
+	if err := p.SetControlMessage(ipv6.FlagGRO, true); err != nil {
+		if protocolNotSupported(err) {
+			t.Skipf("UDP_GRO not supported on %s", runtime.GOOS)
+		}
+		t.Fatal(err)
+	}
+
+	const segSize = 16
+	wb := make([]byte, segSize*4)
+	for i := range wb {
+		wb[i] = byte(i)
+	}
+	cm := ipv6.ControlMessage{GSOSize: segSize}
+	if _, err := p.WriteTo(wb, &cm, c.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	rb := make([]byte, 1500)
+	n, rcm, _, err := p.ReadFrom(rb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n == 0 {
+		t.Fatal("got empty datagram")
+	}
+	if rcm == nil || rcm.GROSize == 0 {
+		// GRO coalescing is best-effort: the kernel is free to
+		// deliver the four segments as separate datagrams instead
+		// of a single coalesced one, depending on timing and NAPI
+		// scheduling, especially under virtualization.
+		t.Skip("kernel did not coalesce the GSO segments into a GRO datagram")
+	}
+	if rcm.GROSize != segSize {
+		t.Fatalf("got GROSize=%d; want %d", rcm.GROSize, segSize)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ipv6_test
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// protocolNotSupported reports whether err is a result of an
+// unimplemented or unsupported protocol or socket option, as opposed
+// to an actual failure communicating with the kernel.
+func protocolNotSupported(err error) bool {
+	switch err := err.(type) {
+	case *net.OpError:
+		return protocolNotSupported(err.Err)
+	case *os.SyscallError:
+		return protocolNotSupported(err.Err)
+	case syscall.Errno:
+		switch err {
+		case syscall.EINVAL, syscall.ENOPROTOOPT, syscall.EPROTONOSUPPORT, syscall.ENOSYS, syscall.EOPNOTSUPP:
+			return true
+		}
+	}
+	return false
+}
+
+// isENOBUFS reports whether err indicates that the kernel's send
+// buffer is temporarily full and the write should be retried.
+func isENOBUFS(err error) bool {
+	switch err := err.(type) {
+	case *net.OpError:
+		return isENOBUFS(err.Err)
+	case *os.SyscallError:
+		return isENOBUFS(err.Err)
+	case syscall.Errno:
+		return err == syscall.ENOBUFS
+	}
+	return false
+}
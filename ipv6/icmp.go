@@ -0,0 +1,86 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ipv6
+
+import "errors"
+
+// ErrNotImplemented is returned by PacketConn's ICMP filter and
+// checksum methods on platforms that don't support them, or when the
+// underlying connection exposes no SyscallConn.
+var ErrNotImplemented = errors.New("not implemented")
+
+// An ICMPType represents a type of ICMP for IPv6 message.
+type ICMPType int
+
+const (
+	ICMPTypeDestinationUnreachable ICMPType = 1
+	ICMPTypePacketTooBig           ICMPType = 2
+	ICMPTypeTimeExceeded           ICMPType = 3
+	ICMPTypeParameterProblem       ICMPType = 4
+	ICMPTypeEchoRequest            ICMPType = 128
+	ICMPTypeEchoReply              ICMPType = 129
+	ICMPTypeMulticastListenerQuery ICMPType = 130
+	ICMPTypeMulticastListenerReport ICMPType = 131
+	ICMPTypeMulticastListenerDone  ICMPType = 132
+	ICMPTypeRouterSolicitation     ICMPType = 133
+	ICMPTypeRouterAdvertisement    ICMPType = 134
+	ICMPTypeNeighborSolicitation   ICMPType = 135
+	ICMPTypeNeighborAdvertisement  ICMPType = 136
+	ICMPTypeRedirect               ICMPType = 137
+)
+
+var icmpTypes = map[ICMPType]string{
+	1:   "destination unreachable",
+	2:   "packet too big",
+	3:   "time exceeded",
+	4:   "parameter problem",
+	128: "echo request",
+	129: "echo reply",
+	130: "multicast listener query",
+	131: "multicast listener report",
+	132: "multicast listener done",
+	133: "router solicitation",
+	134: "router advertisement",
+	135: "neighbor solicitation",
+	136: "neighbor advertisement",
+	137: "redirect",
+}
+
+// String returns the ICMP type name known to this package.
+func (typ ICMPType) String() string {
+	s, ok := icmpTypes[typ]
+	if !ok {
+		return "<nil>"
+	}
+	return s
+}
+
+// An ICMPFilter represents an ICMP message filter for incoming
+// packets, for use with a raw IPv6 ICMP socket.
+type ICMPFilter struct {
+	icmp6Filter
+}
+
+// Accept accepts incoming ICMP packets including the type field
+// value typ.
+func (f *ICMPFilter) Accept(typ ICMPType) {
+	f.accept(typ)
+}
+
+// Block blocks incoming ICMP packets including the type field
+// value typ.
+func (f *ICMPFilter) Block(typ ICMPType) {
+	f.block(typ)
+}
+
+// SetAll sets the filter action to pass or block for all types.
+func (f *ICMPFilter) SetAll(block bool) {
+	f.setAll(block)
+}
+
+// WillBlock reports whether the ICMP type will be blocked.
+func (f *ICMPFilter) WillBlock(typ ICMPType) bool {
+	return f.willBlock(typ)
+}
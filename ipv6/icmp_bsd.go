@@ -0,0 +1,141 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package ipv6
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// icmp6Filter mirrors the kernel's struct icmp6_filter: an 8-word
+// bitmap with one bit per ICMP type. golang.org/x/sys/unix only
+// wraps the ICMPV6_FILTER getsockopt/setsockopt pair on Linux, so the
+// BSDs issue the getsockopt(2)/setsockopt(2) system calls directly.
+type icmp6Filter struct {
+	Filt [8]uint32
+}
+
+func (f *icmp6Filter) accept(typ ICMPType) {
+	f.Filt[typ>>5] |= 1 << (uint32(typ) & 31)
+}
+
+func (f *icmp6Filter) block(typ ICMPType) {
+	f.Filt[typ>>5] &^= 1 << (uint32(typ) & 31)
+}
+
+func (f *icmp6Filter) setAll(block bool) {
+	for i := range f.Filt {
+		if block {
+			f.Filt[i] = 0
+		} else {
+			f.Filt[i] = ^uint32(0)
+		}
+	}
+}
+
+func (f *icmp6Filter) willBlock(typ ICMPType) bool {
+	return f.Filt[typ>>5]&(1<<(uint32(typ)&31)) == 0
+}
+
+// ICMPFilter returns the ICMP filter currently set on c, for use
+// with a raw IPv6 ICMP socket.
+func (c *PacketConn) ICMPFilter() (*ICMPFilter, error) {
+	if !c.ok() {
+		return nil, errInvalidConn
+	}
+	sc, ok := c.c.(interface {
+		SyscallConn() (syscall.RawConn, error)
+	})
+	if !ok {
+		return nil, ErrNotImplemented
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	var f ICMPFilter
+	l := uint32(unsafe.Sizeof(f.icmp6Filter))
+	var serr error
+	cerr := rc.Control(func(fd uintptr) {
+		_, _, errno := syscall.Syscall6(unix.SYS_GETSOCKOPT, fd, uintptr(unix.IPPROTO_ICMPV6), uintptr(unix.ICMPV6_FILTER), uintptr(unsafe.Pointer(&f.icmp6Filter)), uintptr(unsafe.Pointer(&l)), 0)
+		if errno != 0 {
+			serr = errno
+		}
+	})
+	if serr != nil {
+		return nil, serr
+	}
+	if cerr != nil {
+		return nil, cerr
+	}
+	return &f, nil
+}
+
+// SetICMPFilter sets the ICMP filter on c, for use with a raw IPv6
+// ICMP socket.
+func (c *PacketConn) SetICMPFilter(f *ICMPFilter) error {
+	if !c.ok() {
+		return errInvalidConn
+	}
+	sc, ok := c.c.(interface {
+		SyscallConn() (syscall.RawConn, error)
+	})
+	if !ok {
+		return ErrNotImplemented
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+	l := uintptr(unsafe.Sizeof(f.icmp6Filter))
+	var serr error
+	cerr := rc.Control(func(fd uintptr) {
+		_, _, errno := syscall.Syscall6(unix.SYS_SETSOCKOPT, fd, uintptr(unix.IPPROTO_ICMPV6), uintptr(unix.ICMPV6_FILTER), uintptr(unsafe.Pointer(&f.icmp6Filter)), l, 0)
+		if errno != 0 {
+			serr = errno
+		}
+	})
+	if serr != nil {
+		return serr
+	}
+	return cerr
+}
+
+// SetChecksum enables or disables kernel computation of the ICMPv6
+// checksum on a raw ICMP socket. When on, offset gives the byte
+// offset of the checksum field within each outgoing message; when
+// off, the caller is responsible for computing and placing the
+// checksum itself.
+func (c *PacketConn) SetChecksum(on bool, offset int) error {
+	if !c.ok() {
+		return errInvalidConn
+	}
+	sc, ok := c.c.(interface {
+		SyscallConn() (syscall.RawConn, error)
+	})
+	if !ok {
+		return ErrNotImplemented
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+	v := -1
+	if on {
+		v = offset
+	}
+	var serr error
+	cerr := rc.Control(func(fd uintptr) {
+		serr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_CHECKSUM, v)
+	})
+	if serr != nil {
+		return serr
+	}
+	return cerr
+}
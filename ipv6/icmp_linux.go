@@ -0,0 +1,137 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+
+package ipv6
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// icmp6Filter wraps golang.org/x/sys/unix's ICMPv6Filter, which
+// already provides the kernel's ICMPV6_FILTER bitmap and its
+// getsockopt/setsockopt wrappers on Linux. Unlike the BSDs, a set bit
+// in the Linux bitmap blocks the corresponding ICMP type.
+type icmp6Filter struct {
+	unix.ICMPv6Filter
+}
+
+func (f *icmp6Filter) accept(typ ICMPType) {
+	f.Data[typ>>5] &^= 1 << (uint32(typ) & 31)
+}
+
+func (f *icmp6Filter) block(typ ICMPType) {
+	f.Data[typ>>5] |= 1 << (uint32(typ) & 31)
+}
+
+func (f *icmp6Filter) setAll(block bool) {
+	for i := range f.Data {
+		if block {
+			f.Data[i] = ^uint32(0)
+		} else {
+			f.Data[i] = 0
+		}
+	}
+}
+
+func (f *icmp6Filter) willBlock(typ ICMPType) bool {
+	return f.Data[typ>>5]&(1<<(uint32(typ)&31)) != 0
+}
+
+// ICMPFilter returns the ICMP filter currently set on c, for use
+// with a raw IPv6 ICMP socket.
+func (c *PacketConn) ICMPFilter() (*ICMPFilter, error) {
+	if !c.ok() {
+		return nil, errInvalidConn
+	}
+	sc, ok := c.c.(interface {
+		SyscallConn() (syscall.RawConn, error)
+	})
+	if !ok {
+		return nil, ErrNotImplemented
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	var f ICMPFilter
+	var serr error
+	cerr := rc.Control(func(fd uintptr) {
+		v, e := unix.GetsockoptICMPv6Filter(int(fd), unix.IPPROTO_ICMPV6, unix.ICMPV6_FILTER)
+		if e != nil {
+			serr = e
+			return
+		}
+		f.ICMPv6Filter = *v
+	})
+	if serr != nil {
+		return nil, serr
+	}
+	if cerr != nil {
+		return nil, cerr
+	}
+	return &f, nil
+}
+
+// SetICMPFilter sets the ICMP filter on c, for use with a raw IPv6
+// ICMP socket.
+func (c *PacketConn) SetICMPFilter(f *ICMPFilter) error {
+	if !c.ok() {
+		return errInvalidConn
+	}
+	sc, ok := c.c.(interface {
+		SyscallConn() (syscall.RawConn, error)
+	})
+	if !ok {
+		return ErrNotImplemented
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var serr error
+	cerr := rc.Control(func(fd uintptr) {
+		serr = unix.SetsockoptICMPv6Filter(int(fd), unix.IPPROTO_ICMPV6, unix.ICMPV6_FILTER, &f.ICMPv6Filter)
+	})
+	if serr != nil {
+		return serr
+	}
+	return cerr
+}
+
+// SetChecksum enables or disables kernel computation of the ICMPv6
+// checksum on a raw ICMP socket. When on, offset gives the byte
+// offset of the checksum field within each outgoing message; when
+// off, the caller is responsible for computing and placing the
+// checksum itself.
+func (c *PacketConn) SetChecksum(on bool, offset int) error {
+	if !c.ok() {
+		return errInvalidConn
+	}
+	sc, ok := c.c.(interface {
+		SyscallConn() (syscall.RawConn, error)
+	})
+	if !ok {
+		return ErrNotImplemented
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+	v := -1
+	if on {
+		v = offset
+	}
+	var serr error
+	cerr := rc.Control(func(fd uintptr) {
+		serr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_CHECKSUM, v)
+	})
+	if serr != nil {
+		return serr
+	}
+	return cerr
+}
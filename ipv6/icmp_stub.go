@@ -0,0 +1,32 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package ipv6
+
+// icmp6Filter has no kernel backing on this platform; ICMPFilter's
+// methods are no-ops and PacketConn's ICMP filter and checksum
+// methods always report ErrNotImplemented.
+type icmp6Filter struct{}
+
+func (f *icmp6Filter) accept(typ ICMPType)         {}
+func (f *icmp6Filter) block(typ ICMPType)          {}
+func (f *icmp6Filter) setAll(block bool)           {}
+func (f *icmp6Filter) willBlock(typ ICMPType) bool { return false }
+
+// ICMPFilter always reports ErrNotImplemented on this platform.
+func (c *PacketConn) ICMPFilter() (*ICMPFilter, error) {
+	return nil, ErrNotImplemented
+}
+
+// SetICMPFilter always reports ErrNotImplemented on this platform.
+func (c *PacketConn) SetICMPFilter(f *ICMPFilter) error {
+	return ErrNotImplemented
+}
+
+// SetChecksum always reports ErrNotImplemented on this platform.
+func (c *PacketConn) SetChecksum(on bool, offset int) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,14 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix && !linux
+
+package ipv6
+
+// IPV6_NEXTHOP (RFC 2292) is a Linux-only, now-deprecated cmsg type;
+// the BSDs never implemented it.
+const (
+	sysHasNextHop   = false
+	sysIPV6_NEXTHOP = 0
+)
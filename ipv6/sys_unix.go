@@ -0,0 +1,223 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package ipv6
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Logical control message types understood by Marshal/Parse. These
+// are translated to and from the platform's ancillary data levels and
+// types by cmsgLevelType/cmsgLogicalType below. The numeric values
+// themselves come from golang.org/x/sys/unix, which already generates
+// the right constant per GOOS; they must never be hardcoded here,
+// since e.g. Linux's IPV6_TCLASS (0x43) and Darwin's (0x24) differ.
+const (
+	ctlTrafficClass = iota
+	ctlHopLimit
+	ctlPacketInfo
+	ctlNextHop
+	ctlPathMTU
+	ctlGSOSegment // UDP_SEGMENT/UDP_GRO, Linux only
+)
+
+const (
+	pktInfoSize    = 20 // sizeof(struct in6_pktinfo)
+	mtuInfoSize    = 32 // sizeof(struct ip6_mtuinfo)
+	sockaddrIn6Len = 28 // sizeof(struct sockaddr_in6)
+)
+
+var errInvalidControlMessage = errors.New("invalid control message")
+
+// nativeEndian is the byte order of C ints embedded in cmsg payloads
+// (hop limit, traffic class, interface index, MTU, GSO/GRO segment
+// size, ...). Unlike wire-format protocol fields, these are native
+// byte order, not network byte order.
+var nativeEndian binary.ByteOrder
+
+func init() {
+	var x uint16 = 1
+	if *(*byte)(unsafe.Pointer(&x)) == 1 {
+		nativeEndian = binary.LittleEndian
+	} else {
+		nativeEndian = binary.BigEndian
+	}
+}
+
+func cmsgSpace(dataLen int) int {
+	return syscall.CmsgSpace(dataLen)
+}
+
+func cmsgLevelType(ctl int) (level, typ int32, ok bool) {
+	switch ctl {
+	case ctlTrafficClass:
+		return unix.IPPROTO_IPV6, unix.IPV6_TCLASS, true
+	case ctlHopLimit:
+		return unix.IPPROTO_IPV6, unix.IPV6_HOPLIMIT, true
+	case ctlPacketInfo:
+		return unix.IPPROTO_IPV6, unix.IPV6_PKTINFO, true
+	case ctlNextHop:
+		return unix.IPPROTO_IPV6, sysIPV6_NEXTHOP, sysHasNextHop
+	case ctlPathMTU:
+		return unix.IPPROTO_IPV6, unix.IPV6_PATHMTU, true
+	case ctlGSOSegment:
+		return sysSOL_UDP, sysUDP_SEGMENT, gsoSupported
+	default:
+		return 0, 0, false
+	}
+}
+
+func cmsgLogicalType(level, typ int32) (int, bool) {
+	switch {
+	case level == unix.IPPROTO_IPV6 && typ == unix.IPV6_TCLASS:
+		return ctlTrafficClass, true
+	case level == unix.IPPROTO_IPV6 && typ == unix.IPV6_HOPLIMIT:
+		return ctlHopLimit, true
+	case level == unix.IPPROTO_IPV6 && typ == unix.IPV6_PKTINFO:
+		return ctlPacketInfo, true
+	case sysHasNextHop && level == unix.IPPROTO_IPV6 && typ == sysIPV6_NEXTHOP:
+		return ctlNextHop, true
+	case level == unix.IPPROTO_IPV6 && typ == unix.IPV6_PATHMTU:
+		return ctlPathMTU, true
+	case gsoSupported && level == sysSOL_UDP && typ == sysUDP_GRO:
+		return ctlGSOSegment, true
+	default:
+		return 0, false
+	}
+}
+
+func appendCmsg(b []byte, ctl int, data []byte) []byte {
+	level, typ, ok := cmsgLevelType(ctl)
+	if !ok {
+		return b
+	}
+	space := syscall.CmsgSpace(len(data))
+	off := len(b)
+	b = append(b, make([]byte, space)...)
+	h := (*syscall.Cmsghdr)(unsafe.Pointer(&b[off]))
+	h.Level = level
+	h.Type = typ
+	h.SetLen(syscall.CmsgLen(len(data)))
+	copy(b[off+syscall.CmsgLen(0):], data)
+	return b
+}
+
+func nextCmsg(b []byte) (typ int, data, rest []byte, err error) {
+	if len(b) < syscall.CmsgLen(0) {
+		return 0, nil, nil, errInvalidControlMessage
+	}
+	h := (*syscall.Cmsghdr)(unsafe.Pointer(&b[0]))
+	l := int(h.Len)
+	if l < syscall.CmsgLen(0) || l > len(b) {
+		return 0, nil, nil, errInvalidControlMessage
+	}
+	lt, ok := cmsgLogicalType(h.Level, h.Type)
+	data = b[syscall.CmsgLen(0):l]
+	rest = b[syscall.CmsgSpace(l-syscall.CmsgLen(0)):]
+	if !ok {
+		// Unknown ancillary data, skip it.
+		if len(rest) == 0 {
+			return -1, nil, nil, nil
+		}
+		return nextCmsg(rest)
+	}
+	return lt, data, rest, nil
+}
+
+func marshalTrafficClass(v int) []byte {
+	b := make([]byte, 4)
+	nativeEndian.PutUint32(b, uint32(v))
+	return b
+}
+
+func parseTrafficClass(b []byte) int {
+	if len(b) < 4 {
+		return 0
+	}
+	return int(nativeEndian.Uint32(b))
+}
+
+func marshalHopLimit(v int) []byte {
+	b := make([]byte, 4)
+	nativeEndian.PutUint32(b, uint32(v))
+	return b
+}
+
+func parseHopLimit(b []byte) int {
+	if len(b) < 4 {
+		return 0
+	}
+	return int(nativeEndian.Uint32(b))
+}
+
+// marshalPacketInfo encodes a struct in6_pktinfo: a 16-byte address
+// followed by a 4-byte interface index.
+func marshalPacketInfo(ip net.IP, ifIndex int) []byte {
+	b := make([]byte, pktInfoSize)
+	if ip16 := ip.To16(); ip16 != nil {
+		copy(b[:16], ip16)
+	}
+	nativeEndian.PutUint32(b[16:20], uint32(ifIndex))
+	return b
+}
+
+func parsePacketInfo(b []byte) (net.IP, int) {
+	if len(b) < pktInfoSize {
+		return nil, 0
+	}
+	ip := make(net.IP, 16)
+	copy(ip, b[:16])
+	ifIndex := int(nativeEndian.Uint32(b[16:20]))
+	return ip, ifIndex
+}
+
+// marshalNextHop encodes a struct sockaddr_in6: family(2) + port(2) +
+// flowinfo(4) + address(16) + scope_id(4), with the address at
+// offset 8, not the in6_pktinfo layout used by marshalPacketInfo.
+func marshalNextHop(ip net.IP) []byte {
+	b := make([]byte, sockaddrIn6Len)
+	nativeEndian.PutUint16(b[:2], uint16(unix.AF_INET6))
+	if ip16 := ip.To16(); ip16 != nil {
+		copy(b[8:24], ip16)
+	}
+	return b
+}
+
+// parsePathMTU decodes a struct ip6_mtuinfo: a 28-byte sockaddr_in6,
+// whose address sits at offset 8, followed by a 4-byte MTU.
+func parsePathMTU(b []byte) (net.IP, int) {
+	if len(b) < mtuInfoSize {
+		return nil, 0
+	}
+	ip := make(net.IP, 16)
+	copy(ip, b[8:24])
+	mtu := int(nativeEndian.Uint32(b[28:32]))
+	return ip, mtu
+}
+
+// marshalGSOSize encodes a UDP_SEGMENT cmsg payload: a single __u16
+// holding the requested segment size.
+func marshalGSOSize(v int) []byte {
+	b := make([]byte, 2)
+	nativeEndian.PutUint16(b, uint16(v))
+	return b
+}
+
+// parseGROSize decodes a UDP_GRO cmsg payload: a single __u16 holding
+// the size of each coalesced segment in the received datagram.
+func parseGROSize(b []byte) int {
+	if len(b) < 2 {
+		return 0
+	}
+	return int(nativeEndian.Uint16(b))
+}
@@ -0,0 +1,59 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+
+package ipv6
+
+import (
+	"errors"
+	"net"
+)
+
+var errCmsgNotImplemented = errors.New("ipv6: control message not implemented on windows")
+
+// Windows does not support IPv6 ancillary data, so ControlMessage is
+// always empty on this platform.
+
+const (
+	ctlTrafficClass = iota
+	ctlHopLimit
+	ctlPacketInfo
+	ctlNextHop
+	ctlPathMTU
+	ctlGSOSegment
+)
+
+const (
+	pktInfoSize = 0
+	mtuInfoSize = 0
+)
+
+func cmsgSpace(dataLen int) int { return 0 }
+
+func appendCmsg(b []byte, ctl int, data []byte) []byte { return b }
+
+func nextCmsg(b []byte) (typ int, data, rest []byte, err error) {
+	return 0, nil, nil, errCmsgNotImplemented
+}
+
+func marshalTrafficClass(v int) []byte { return nil }
+func parseTrafficClass(b []byte) int   { return 0 }
+func marshalHopLimit(v int) []byte     { return nil }
+func parseHopLimit(b []byte) int       { return 0 }
+
+func marshalPacketInfo(ip net.IP, ifIndex int) []byte { return nil }
+func parsePacketInfo(b []byte) (net.IP, int)          { return nil, 0 }
+func marshalNextHop(ip net.IP) []byte                 { return nil }
+func parsePathMTU(b []byte) (net.IP, int)             { return nil, 0 }
+
+func marshalGSOSize(v int) []byte { return nil }
+func parseGROSize(b []byte) int   { return 0 }
+
+func setUDPGRO(c net.PacketConn, on bool) error {
+	if !on {
+		return nil
+	}
+	return errGSONotSupported
+}